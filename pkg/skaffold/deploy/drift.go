@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/driftdetector"
+)
+
+// DriftPollInterval is how often DetectDrift re-checks the cluster while
+// `skaffold dev --detect-drift` is running.
+const DriftPollInterval = 10 * time.Second
+
+// OnDrift is notified whenever drift is detected, once per poll that found
+// any. Callers wire this up to the event bus and, for `--detect-drift`, to
+// triggering a re-sync.
+type OnDrift func(drifts []driftdetector.Drift)
+
+// DetectDrift polls detector on DriftPollInterval until ctx is done,
+// printing a summary and invoking onDrift whenever live resources have
+// diverged from what Skaffold applied.
+func DetectDrift(ctx context.Context, detector *driftdetector.Detector, namespace string, onDrift OnDrift, out io.Writer) {
+	ticker := time.NewTicker(DriftPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			drifts, err := detector.Detect(ctx, namespace)
+			if err != nil {
+				fmt.Fprintf(out, "drift detection failed: %s\n", err)
+				continue
+			}
+			if len(drifts) == 0 {
+				continue
+			}
+
+			printDriftSummary(out, drifts)
+			if onDrift != nil {
+				onDrift(drifts)
+			}
+		}
+	}
+}
+
+// printDriftSummary prints one line per drifted field, in the same style
+// as printStatusCheckSummary, e.g.:
+//
+//	- test:deployment/dep drifted: spec.replicas 3 -> 5
+func printDriftSummary(out io.Writer, drifts []driftdetector.Drift) {
+	for _, d := range drifts {
+		kind := strings.ToLower(d.Kind)
+		var resourceName string
+		if d.Namespace == "default" {
+			resourceName = fmt.Sprintf("%s/%s", kind, d.Name)
+		} else {
+			resourceName = fmt.Sprintf("%s:%s/%s", d.Namespace, kind, d.Name)
+		}
+		fmt.Fprintf(out, " - %s drifted: %s %s -> %s\n", resourceName, d.Path, d.Desired, d.Live)
+	}
+}
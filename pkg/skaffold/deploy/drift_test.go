@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/driftdetector"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestPrintDriftSummary(t *testing.T) {
+	tests := []struct {
+		description string
+		drifts      []driftdetector.Drift
+		expected    string
+	}{
+		{
+			description: "single field drifted",
+			drifts: []driftdetector.Drift{
+				{
+					Resource:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+					Kind:      "Deployment",
+					Name:      "dep",
+					Namespace: "test",
+					Path:      "spec.replicas",
+					Desired:   "3",
+					Live:      "5",
+				},
+			},
+			expected: " - test:deployment/dep drifted: spec.replicas 3 -> 5\n",
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			out := new(bytes.Buffer)
+			printDriftSummary(out, test.drifts)
+			t.CheckDeepEqual(test.expected, out.String())
+		})
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package driftdetector periodically diffs the manifests Skaffold applied
+// during a run against the live state of the cluster, so that manual
+// changes made outside of Skaffold (`kubectl edit`, a dashboard, an
+// operator) are surfaced instead of silently masked on the next deploy.
+package driftdetector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// LastAppliedAnnotation records, kubectl-apply style, the manifest Skaffold
+// applied the previous run. Detect uses it as the baseline of a three-way
+// merge so that fields Kubernetes or a controller/webhook defaulted on
+// their own - never present in what Skaffold applied - aren't reported as
+// drift just because they differ from the freshly rendered desired state.
+const LastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// DefaultIgnorePaths are fields that routinely change without user
+// intervention - Kubernetes system bookkeeping - and would otherwise cause
+// false-positive drift reports. spec.replicas is additionally ignored, but
+// only for resources scaled by a HorizontalPodAutoscaler; see hasHPAOwner.
+var DefaultIgnorePaths = []string{
+	"status",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.managedFields",
+}
+
+// hpaIgnorePaths is appended to DefaultIgnorePaths only for resources with
+// an HPA owner reference, per DefaultIgnorePaths' doc comment.
+var hpaIgnorePaths = [][]string{{"spec", "replicas"}}
+
+// Drift describes a single field whose live value has diverged from what
+// Skaffold last applied.
+type Drift struct {
+	Resource  schema.GroupVersionResource
+	Kind      string
+	Name      string
+	Namespace string
+	Path      string
+	Desired   string
+	Live      string
+}
+
+// Detector polls the resources Skaffold deployed during this run and
+// reports fields whose live value no longer matches what was applied.
+type Detector struct {
+	dynamicClient dynamic.Interface
+	ignorePaths   [][]string
+	desired       map[schema.GroupVersionResource][]*unstructured.Unstructured
+}
+
+// NewDetector creates a Detector over desired, the manifests Skaffold
+// applied during this run, keyed by their GroupVersionResource. ignorePaths
+// are dotted field paths (e.g. "spec.replicas") excluded from comparison in
+// addition to DefaultIgnorePaths.
+func NewDetector(dynamicClient dynamic.Interface, desired map[schema.GroupVersionResource][]*unstructured.Unstructured, ignorePaths ...string) *Detector {
+	all := append(append([]string{}, DefaultIgnorePaths...), ignorePaths...)
+	split := make([][]string, len(all))
+	for i, p := range all {
+		split[i] = strings.Split(p, ".")
+	}
+
+	return &Detector{
+		dynamicClient: dynamicClient,
+		ignorePaths:   split,
+		desired:       desired,
+	}
+}
+
+// Detect fetches the live state of every desired resource in ns and
+// returns the drift found, if any. A resource that no longer exists live is
+// treated as a deletion, not drift, and is skipped.
+func (d *Detector) Detect(ctx context.Context, ns string) ([]Drift, error) {
+	var drifts []Drift
+
+	for gvr, manifests := range d.desired {
+		for _, desired := range manifests {
+			live, err := d.dynamicClient.Resource(gvr).Namespace(ns).Get(ctx, desired.GetName(), metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+
+			baseline, ok := lastApplied(live)
+			if !ok {
+				// No last-applied baseline recorded (e.g. the resource
+				// predates drift detection, or wasn't created through an
+				// annotated apply) - fall back to a two-way diff against
+				// desired rather than reporting nothing.
+				baseline = desired.Object
+			}
+
+			ignore := d.ignorePaths
+			if hasHPAOwner(live) {
+				ignore = append(append([][]string{}, d.ignorePaths...), hpaIgnorePaths...)
+			}
+
+			for _, leaf := range diffLeaves(nil, desired.Object, live.Object, baseline, ignore) {
+				drifts = append(drifts, Drift{
+					Resource:  gvr,
+					Kind:      desired.GetKind(),
+					Name:      desired.GetName(),
+					Namespace: ns,
+					Path:      strings.Join(leaf.path, "."),
+					Desired:   leaf.desired,
+					Live:      leaf.live,
+				})
+			}
+		}
+	}
+
+	return drifts, nil
+}
+
+// lastApplied extracts and parses live's LastAppliedAnnotation, if present.
+func lastApplied(live *unstructured.Unstructured) (map[string]interface{}, bool) {
+	raw, ok := live.GetAnnotations()[LastAppliedAnnotation]
+	if !ok {
+		return nil, false
+	}
+
+	var baseline map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &baseline); err != nil {
+		return nil, false
+	}
+	return baseline, true
+}
+
+// hasHPAOwner reports whether live is scaled by a HorizontalPodAutoscaler,
+// the condition under which its spec.replicas is excluded from drift.
+func hasHPAOwner(live *unstructured.Unstructured) bool {
+	for _, ref := range live.GetOwnerReferences() {
+		if ref.Kind == "HorizontalPodAutoscaler" {
+			return true
+		}
+	}
+	return false
+}
+
+type leafDiff struct {
+	path          []string
+	desired, live string
+}
+
+// diffLeaves walks desired and live in lock-step and returns every leaf
+// value that differs, skipping any path prefixed by one in ignore. A leaf
+// absent from baseline - the last-applied configuration - is something
+// Kubernetes or a controller/webhook added on its own, not a field
+// Skaffold owns, so it's never reported even if desired and live disagree
+// about it; this is what makes the comparison a three-way merge rather
+// than a plain desired-vs-live diff.
+func diffLeaves(prefix []string, desired, live, baseline interface{}, ignore [][]string) []leafDiff {
+	if hasPrefix(prefix, ignore) {
+		return nil
+	}
+
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	liveMap, liveIsMap := live.(map[string]interface{})
+	baselineMap, baselineIsMap := baseline.(map[string]interface{})
+	if desiredIsMap || liveIsMap || baselineIsMap {
+		keys := map[string]bool{}
+		for k := range desiredMap {
+			keys[k] = true
+		}
+		for k := range liveMap {
+			keys[k] = true
+		}
+		for k := range baselineMap {
+			keys[k] = true
+		}
+
+		var diffs []leafDiff
+		for k := range keys {
+			childPrefix := append(append([]string{}, prefix...), k)
+			diffs = append(diffs, diffLeaves(childPrefix, desiredMap[k], liveMap[k], baselineMap[k], ignore)...)
+		}
+		return diffs
+	}
+
+	if baseline == nil {
+		return nil
+	}
+
+	if !reflect.DeepEqual(desired, live) {
+		return []leafDiff{{path: prefix, desired: toString(desired), live: toString(live)}}
+	}
+	return nil
+}
+
+func hasPrefix(path []string, ignore [][]string) bool {
+	for _, p := range ignore {
+		if len(path) < len(p) {
+			continue
+		}
+		match := true
+		for i, seg := range p {
+			if path[i] != seg {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func toString(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	return fmt.Sprintf("%v", v)
+}
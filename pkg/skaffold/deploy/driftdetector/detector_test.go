@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driftdetector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+var deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+func deployment(replicas int64, image string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "dep1",
+			"namespace": "test",
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": image},
+					},
+				},
+			},
+		},
+	}}
+}
+
+// withLastApplied stamps live with the kubectl-apply-style annotation
+// recording lastApplied as what Skaffold applied the previous run, so
+// Detect can tell a field it owns from one Kubernetes defaulted on its own.
+func withLastApplied(live, lastApplied *unstructured.Unstructured) *unstructured.Unstructured {
+	raw, err := json.Marshal(lastApplied.Object)
+	if err != nil {
+		panic(err)
+	}
+	metadata := live.Object["metadata"].(map[string]interface{})
+	metadata["annotations"] = map[string]interface{}{LastAppliedAnnotation: string(raw)}
+	return live
+}
+
+// withHPAOwner marks live as scaled by a HorizontalPodAutoscaler, the
+// condition under which drift detection ignores spec.replicas.
+func withHPAOwner(live *unstructured.Unstructured) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: live.Object}
+	u.SetOwnerReferences([]metav1.OwnerReference{{Kind: "HorizontalPodAutoscaler", Name: "dep1-hpa"}})
+	return u
+}
+
+func TestDetectorDetect(t *testing.T) {
+	tests := []struct {
+		description string
+		live        *unstructured.Unstructured
+		expected    []Drift
+	}{
+		{
+			description: "live state matches desired",
+			live:        withLastApplied(deployment(3, "app:v1"), deployment(3, "app:v1")),
+		},
+		{
+			description: "live replicas manually edited are reported as drift",
+			live:        withLastApplied(deployment(5, "app:v1"), deployment(3, "app:v1")),
+			expected: []Drift{
+				{
+					Resource:  deploymentsGVR,
+					Kind:      "Deployment",
+					Name:      "dep1",
+					Namespace: "test",
+					Path:      "spec.replicas",
+					Desired:   "3",
+					Live:      "5",
+				},
+			},
+		},
+		{
+			description: "live replicas changed on an HPA-owned deployment are ignored",
+			live:        withHPAOwner(withLastApplied(deployment(5, "app:v1"), deployment(3, "app:v1"))),
+		},
+		{
+			description: "live image drifted",
+			live:        withLastApplied(deployment(3, "app:v2"), deployment(3, "app:v1")),
+			expected: []Drift{
+				{
+					Resource:  deploymentsGVR,
+					Kind:      "Deployment",
+					Name:      "dep1",
+					Namespace: "test",
+					Path:      "spec.template.spec.containers",
+					Desired:   "[map[image:app:v1 name:app]]",
+					Live:      "[map[image:app:v2 name:app]]",
+				},
+			},
+		},
+		{
+			description: "fields absent from the last-applied configuration are not reported, even if they differ from desired",
+			live: func() *unstructured.Unstructured {
+				live := deployment(3, "app:v1")
+				live.Object["status"] = map[string]interface{}{"observedGeneration": int64(2)}
+				spec := live.Object["spec"].(map[string]interface{})
+				spec["minReadySeconds"] = int64(5) // e.g. defaulted server-side, never in desired or last-applied
+				return withLastApplied(live, deployment(3, "app:v1"))
+			}(),
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			desired := deployment(3, "app:v1")
+			scheme := runtime.NewScheme()
+			client := dynamicfake.NewSimpleDynamicClient(scheme, test.live)
+
+			detector := NewDetector(client, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+				deploymentsGVR: {desired},
+			})
+
+			actual, err := detector.Detect(context.Background(), "test")
+			t.CheckErrorAndDeepEqual(false, err, test.expected, actual)
+		})
+	}
+}
+
+func TestDetectorDetectFallsBackToTwoWayDiffWithoutLastApplied(t *testing.T) {
+	testutil.Run(t, "a resource with no last-applied annotation is still diffed against desired", func(t *testutil.T) {
+		desired := deployment(3, "app:v1")
+		live := deployment(3, "app:v2") // no withLastApplied - e.g. created outside Skaffold entirely
+		scheme := runtime.NewScheme()
+		client := dynamicfake.NewSimpleDynamicClient(scheme, live)
+
+		detector := NewDetector(client, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+			deploymentsGVR: {desired},
+		})
+
+		actual, err := detector.Detect(context.Background(), "test")
+		expected := []Drift{
+			{
+				Resource:  deploymentsGVR,
+				Kind:      "Deployment",
+				Name:      "dep1",
+				Namespace: "test",
+				Path:      "spec.template.spec.containers",
+				Desired:   "[map[image:app:v1 name:app]]",
+				Live:      "[map[image:app:v2 name:app]]",
+			},
+		}
+		t.CheckErrorAndDeepEqual(false, err, expected, actual)
+	})
+}
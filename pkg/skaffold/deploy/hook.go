@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/hooks"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/resource"
+)
+
+// RunHooks runs every lifecycle hook annotated on manifests for phase, in
+// ascending `skaffold.dev/hook-weight` order. Each hook is a first-class
+// Resource - polled for readiness with the exact same pollUntilDone
+// StatusCheck uses for Deployments, Jobs, Pods and the rest - so the next
+// hook isn't created until the previous one reaches Complete/Succeeded. A
+// hook that fails or misses its own per-hook deadline stops the phase:
+// RunHooks returns its error and the remaining hooks are not created.
+func RunHooks(ctx context.Context, dynamicClient dynamic.Interface, client kubernetes.Interface, manifests []*unstructured.Unstructured, gvrFor func(*unstructured.Unstructured) schema.GroupVersionResource, phase hooks.Phase) error {
+	toRun, err := hooks.ForPhase(manifests, gvrFor, phase)
+	if err != nil {
+		return fmt.Errorf("resolving %s hooks: %w", phase, err)
+	}
+
+	for _, h := range toRun {
+		if err := runHook(ctx, dynamicClient, client, h); err != nil {
+			return fmt.Errorf("running %s hook %s/%s: %w", h.Phase, h.Object.GetNamespace(), h.Object.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// runHook creates h's resource, blocks until it stabilizes, and applies
+// h's delete policy.
+func runHook(ctx context.Context, dynamicClient dynamic.Interface, client kubernetes.Interface, h hooks.Hook) error {
+	if h.DeletePolicy == hooks.DeletePolicyBeforeHookCreation {
+		deleteHookResource(ctx, dynamicClient, h)
+	}
+
+	if _, err := dynamicClient.Resource(h.GVR).Namespace(h.Object.GetNamespace()).Create(ctx, h.Object, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("creating hook resource: %w", err)
+	}
+
+	r := resourceForHook(h)
+	pollUntilDone(ctx, client, dynamicClient, r)
+
+	succeeded := r.Status().Error() == nil
+	if shouldDeleteHook(h.DeletePolicy, succeeded) {
+		deleteHookResource(ctx, dynamicClient, h)
+	}
+
+	return r.Status().Error()
+}
+
+// resourceForHook returns the Resource that knows how to poll h's
+// underlying kind, reusing the same readiness rules the status checker
+// already applies to Jobs and Pods deployed outside the hook lifecycle.
+func resourceForHook(h hooks.Hook) Resource {
+	switch h.Object.GetKind() {
+	case "Pod":
+		return resource.NewPod(h.Object.GetName(), h.Object.GetNamespace(), h.Deadline)
+	case "Job":
+		return resource.NewJob(h.Object.GetName(), h.Object.GetNamespace(), h.Deadline)
+	default:
+		return newHookResource(h)
+	}
+}
+
+// hookResource is the Resource for a hook kind Skaffold has no readiness
+// checker for (e.g. a ConfigMap used only to trigger another hook). It has
+// no CheckStatus method, so pollUntilDone's resolveChecker treats it like
+// any other kind Skaffold can't inspect: stabilized as soon as it exists.
+type hookResource struct {
+	name      string
+	namespace string
+	status    resource.Status
+	done      bool
+	deadline  time.Duration
+}
+
+func newHookResource(h hooks.Hook) *hookResource {
+	return &hookResource{
+		name:      h.Object.GetName(),
+		namespace: h.Object.GetNamespace(),
+		status:    resource.NewStatus("", nil),
+		deadline:  h.Deadline,
+	}
+}
+
+func (r *hookResource) Deadline() time.Duration { return r.deadline }
+
+func (r *hookResource) UpdateStatus(details string, err error) {
+	r.status = resource.NewStatus(details, err)
+}
+
+func (r *hookResource) MarkDone() { r.done = true }
+
+func (r *hookResource) IsStatusCheckComplete() bool { return r.done }
+
+func (r *hookResource) Status() resource.Status { return r.status }
+
+func (r *hookResource) String() string {
+	if r.namespace == "default" {
+		return fmt.Sprintf("hook/%s", r.name)
+	}
+	return fmt.Sprintf("%s:hook/%s", r.namespace, r.name)
+}
+
+func shouldDeleteHook(policy hooks.DeletePolicy, succeeded bool) bool {
+	switch policy {
+	case hooks.DeletePolicyHookSucceeded:
+		return succeeded
+	case hooks.DeletePolicyHookFailed:
+		return !succeeded
+	default:
+		return false
+	}
+}
+
+func deleteHookResource(ctx context.Context, dynamicClient dynamic.Interface, h hooks.Hook) {
+	_ = dynamicClient.Resource(h.GVR).Namespace(h.Object.GetNamespace()).Delete(ctx, h.Object.GetName(), metav1.DeleteOptions{})
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/hooks"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+var hookJobGVR = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+func namedJobHook(name string, annotations map[string]string) *unstructured.Unstructured {
+	merged := map[string]string{hooks.Annotation: "post-install"}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "test",
+		},
+	}}
+	u.SetAnnotations(merged)
+	return u
+}
+
+func gvrForJob(*unstructured.Unstructured) schema.GroupVersionResource { return hookJobGVR }
+
+func TestRunHooksSucceedsAndCleansUpOnSuccess(t *testing.T) {
+	testutil.Run(t, "a completed job hook is deleted per the default delete policy", func(t *testutil.T) {
+		manifest := namedJobHook("migrate", nil)
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		client := fakekubeclientset.NewSimpleClientset(&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "test"},
+			Status:     batchv1.JobStatus{Succeeded: 1},
+		})
+
+		err := RunHooks(context.Background(), dynamicClient, client, []*unstructured.Unstructured{manifest}, gvrForJob, hooks.PhasePostInstall)
+		t.CheckError(false, err)
+
+		if _, err := dynamicClient.Resource(hookJobGVR).Namespace("test").Get(context.Background(), "migrate", metav1.GetOptions{}); err == nil {
+			t.Errorf("expected hook resource to have been deleted after succeeding")
+		}
+	})
+}
+
+func TestRunHooksTimesOutWhenHookNeverCompletes(t *testing.T) {
+	testutil.Run(t, "a job that never reports completion times out", func(t *testutil.T) {
+		manifest := namedJobHook("migrate", map[string]string{hooks.DeadlineAnnotation: "0"})
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		client := fakekubeclientset.NewSimpleClientset() // job never found => never reports complete
+
+		err := RunHooks(context.Background(), dynamicClient, client, []*unstructured.Unstructured{manifest}, gvrForJob, hooks.PhasePostInstall)
+		t.CheckErrorContains("could not stabilize within", err)
+	})
+}
+
+func TestRunHooksExecutesSequentiallyInOrder(t *testing.T) {
+	testutil.Run(t, "hooks run one at a time, in ascending weight order", func(t *testutil.T) {
+		manifests := []*unstructured.Unstructured{
+			namedJobHook("heaviest", map[string]string{hooks.WeightAnnotation: "10"}),
+			namedJobHook("lightest", map[string]string{hooks.WeightAnnotation: "-5"}),
+			namedJobHook("middle", nil),
+		}
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		// All three jobs report success immediately, so ordering - not
+		// completion timing - is what the assertion below verifies.
+		client := fakekubeclientset.NewSimpleClientset(
+			&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "heaviest", Namespace: "test"}, Status: batchv1.JobStatus{Succeeded: 1}},
+			&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "lightest", Namespace: "test"}, Status: batchv1.JobStatus{Succeeded: 1}},
+			&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "middle", Namespace: "test"}, Status: batchv1.JobStatus{Succeeded: 1}},
+		)
+
+		err := RunHooks(context.Background(), dynamicClient, client, manifests, gvrForJob, hooks.PhasePostInstall)
+		t.CheckError(false, err)
+
+		var created []string
+		for _, action := range dynamicClient.Actions() {
+			createAction, ok := action.(k8stesting.CreateAction)
+			if !ok {
+				continue
+			}
+			created = append(created, createAction.GetObject().(*unstructured.Unstructured).GetName())
+		}
+		t.CheckDeepEqual([]string{"lightest", "middle", "heaviest"}, created)
+	})
+}
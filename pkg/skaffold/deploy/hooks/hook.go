@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hooks runs the annotated Kubernetes resources (Jobs, Pods,
+// ConfigMap-triggered scripts) that make up a Skaffold deploy's lifecycle
+// hooks, modeled on Helm's hook contract.
+package hooks
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// Annotation marks a resource as a lifecycle hook, e.g.
+	// `skaffold.dev/hook: post-install`.
+	Annotation = "skaffold.dev/hook"
+	// WeightAnnotation orders hooks within the same phase; lower runs
+	// first. Hooks without it default to weight 0.
+	WeightAnnotation = "skaffold.dev/hook-weight"
+	// DeletePolicyAnnotation controls when a hook resource is cleaned up.
+	// Hooks without it default to DeletePolicyHookSucceeded.
+	DeletePolicyAnnotation = "skaffold.dev/hook-delete-policy"
+	// DeadlineAnnotation overrides, in seconds, how long Skaffold waits for
+	// this hook to reach Complete/Succeeded - analogous to a Deployment's
+	// `ProgressDeadlineSeconds`. Hooks without it default to
+	// DefaultDeadline.
+	DeadlineAnnotation = "skaffold.dev/hook-deadline-seconds"
+)
+
+// DefaultDeadline is how long Skaffold waits for a hook to reach
+// Complete/Succeeded when it isn't annotated with DeadlineAnnotation.
+const DefaultDeadline = 5 * time.Minute
+
+// Phase is a point in the deploy lifecycle at which hooks can run.
+type Phase string
+
+const (
+	PhasePreInstall  Phase = "pre-install"
+	PhasePostInstall Phase = "post-install"
+	PhasePreUpgrade  Phase = "pre-upgrade"
+	PhasePostUpgrade Phase = "post-upgrade"
+	PhasePreDelete   Phase = "pre-delete"
+	PhasePostDelete  Phase = "post-delete"
+)
+
+// DeletePolicy controls when a hook resource is cleaned up.
+type DeletePolicy string
+
+const (
+	DeletePolicyHookSucceeded      DeletePolicy = "hook-succeeded"
+	DeletePolicyHookFailed         DeletePolicy = "hook-failed"
+	DeletePolicyBeforeHookCreation DeletePolicy = "before-hook-creation"
+)
+
+// Hook is a single annotated resource that should run at Phase.
+type Hook struct {
+	Phase        Phase
+	Weight       int
+	DeletePolicy DeletePolicy
+	Deadline     time.Duration
+	Object       *unstructured.Unstructured
+	GVR          schema.GroupVersionResource
+}
+
+// Parse inspects u's annotations and returns the Hook it describes, or
+// found=false if u isn't annotated as a hook at all.
+func Parse(u *unstructured.Unstructured, gvr schema.GroupVersionResource) (hook Hook, found bool, err error) {
+	annotations := u.GetAnnotations()
+	phaseStr, ok := annotations[Annotation]
+	if !ok {
+		return Hook{}, false, nil
+	}
+
+	phase := Phase(phaseStr)
+	switch phase {
+	case PhasePreInstall, PhasePostInstall, PhasePreUpgrade, PhasePostUpgrade, PhasePreDelete, PhasePostDelete:
+	default:
+		return Hook{}, false, fmt.Errorf("unknown %s phase %q on %s/%s", Annotation, phaseStr, u.GetNamespace(), u.GetName())
+	}
+
+	weight := 0
+	if raw, ok := annotations[WeightAnnotation]; ok {
+		weight, err = strconv.Atoi(raw)
+		if err != nil {
+			return Hook{}, false, fmt.Errorf("invalid %s %q on %s/%s: %w", WeightAnnotation, raw, u.GetNamespace(), u.GetName(), err)
+		}
+	}
+
+	deletePolicy := DeletePolicyHookSucceeded
+	if raw, ok := annotations[DeletePolicyAnnotation]; ok {
+		deletePolicy = DeletePolicy(raw)
+	}
+
+	deadline := DefaultDeadline
+	if raw, ok := annotations[DeadlineAnnotation]; ok {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil {
+			return Hook{}, false, fmt.Errorf("invalid %s %q on %s/%s: %w", DeadlineAnnotation, raw, u.GetNamespace(), u.GetName(), err)
+		}
+		deadline = time.Duration(seconds) * time.Second
+	}
+
+	return Hook{
+		Phase:        phase,
+		Weight:       weight,
+		DeletePolicy: deletePolicy,
+		Deadline:     deadline,
+		Object:       u,
+		GVR:          gvr,
+	}, true, nil
+}
+
+// ForPhase parses manifests and returns the hooks registered for phase,
+// ordered by ascending weight.
+func ForPhase(manifests []*unstructured.Unstructured, gvrFor func(*unstructured.Unstructured) schema.GroupVersionResource, phase Phase) ([]Hook, error) {
+	var hooks []Hook
+	for _, m := range manifests {
+		hook, found, err := Parse(m, gvrFor(m))
+		if err != nil {
+			return nil, err
+		}
+		if found && hook.Phase == phase {
+			hooks = append(hooks, hook)
+		}
+	}
+
+	sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].Weight < hooks[j].Weight })
+	return hooks, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+var jobGVR = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+func namedJob(name string, annotations map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "test",
+		},
+	}}
+	u.SetAnnotations(annotations)
+	return u
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		description string
+		annotations map[string]string
+		expected    Hook
+		found       bool
+		shouldErr   bool
+	}{
+		{
+			description: "not a hook",
+			annotations: map[string]string{},
+		},
+		{
+			description: "minimal hook defaults weight, delete policy and deadline",
+			annotations: map[string]string{Annotation: "post-install"},
+			expected:    Hook{Phase: PhasePostInstall, Weight: 0, DeletePolicy: DeletePolicyHookSucceeded, Deadline: DefaultDeadline},
+			found:       true,
+		},
+		{
+			description: "fully specified hook",
+			annotations: map[string]string{
+				Annotation:             "pre-upgrade",
+				WeightAnnotation:       "-5",
+				DeletePolicyAnnotation: "before-hook-creation",
+				DeadlineAnnotation:     "30",
+			},
+			expected: Hook{Phase: PhasePreUpgrade, Weight: -5, DeletePolicy: DeletePolicyBeforeHookCreation, Deadline: 30 * time.Second},
+			found:    true,
+		},
+		{
+			description: "unknown phase",
+			annotations: map[string]string{Annotation: "mid-install"},
+			shouldErr:   true,
+		},
+		{
+			description: "non-integer weight",
+			annotations: map[string]string{Annotation: "post-install", WeightAnnotation: "soon"},
+			shouldErr:   true,
+		},
+		{
+			description: "non-integer deadline",
+			annotations: map[string]string{Annotation: "post-install", DeadlineAnnotation: "soon"},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			u := namedJob("job1", test.annotations)
+			hook, found, err := Parse(u, jobGVR)
+
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.found, found)
+			if test.found {
+				test.expected.Object = u
+				test.expected.GVR = jobGVR
+				t.CheckDeepEqual(test.expected, hook)
+			}
+		})
+	}
+}
+
+func TestForPhaseOrdersByWeight(t *testing.T) {
+	testutil.Run(t, "hooks in the same phase are ordered by ascending weight", func(t *testutil.T) {
+		manifests := []*unstructured.Unstructured{
+			namedJob("heaviest", map[string]string{Annotation: "post-install", WeightAnnotation: "10"}),
+			namedJob("lightest", map[string]string{Annotation: "post-install", WeightAnnotation: "-5"}),
+			namedJob("middle", map[string]string{Annotation: "post-install"}),
+			namedJob("other-phase", map[string]string{Annotation: "pre-install"}),
+		}
+
+		hooks, err := ForPhase(manifests, func(*unstructured.Unstructured) schema.GroupVersionResource { return jobGVR }, PhasePostInstall)
+		t.CheckError(false, err)
+
+		var names []string
+		for _, h := range hooks {
+			names = append(names, h.Object.GetName())
+		}
+		t.CheckDeepEqual([]string{"lightest", "middle", "heaviest"}, names)
+	})
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RunIDLabel is applied to every resource Skaffold deploys so that later
+// commands (e.g. `skaffold deploy --status-check`) can identify which
+// resources belong to this particular run.
+const RunIDLabel = "skaffold.dev/run-id"
+
+// Labeller adds labels to deployed resources and knows how to select them
+// back out of the cluster.
+type Labeller struct {
+	runID  string
+	labels map[string]string
+}
+
+// NewLabeller creates a new Labeller for this run. If runID is empty, a new
+// one is generated.
+func NewLabeller(runID string, customLabels ...string) *Labeller {
+	if runID == "" {
+		runID = uuid.New().String()
+	}
+
+	labels := map[string]string{
+		RunIDLabel: runID,
+	}
+	for _, l := range customLabels {
+		parts := splitLabel(l)
+		if len(parts) != 2 {
+			continue
+		}
+		labels[parts[0]] = parts[1]
+	}
+
+	return &Labeller{
+		runID:  runID,
+		labels: labels,
+	}
+}
+
+func splitLabel(label string) []string {
+	for i := range label {
+		if label[i] == '=' {
+			return []string{label[:i], label[i+1:]}
+		}
+	}
+	return nil
+}
+
+// Labels returns the full set of labels that should be applied to resources
+// deployed during this run.
+func (l *Labeller) Labels() map[string]string {
+	return l.labels
+}
+
+// RunIDSelector returns a label selector that matches only resources
+// deployed during this run.
+func (l *Labeller) RunIDSelector() string {
+	return fmt.Sprintf("%s=%s", RunIDLabel, l.runID)
+}
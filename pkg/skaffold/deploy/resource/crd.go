@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// conditionsHealthChecker is the generic, built-in HealthChecker used for
+// any CRD that follows the common `status.conditions[].type/status`
+// convention - Knative Services and cert-manager Certificates among them.
+type conditionsHealthChecker struct{}
+
+func (conditionsHealthChecker) Check(u *unstructured.Unstructured) (HealthStatus, string, error) {
+	conditions, found, err := unstructured.NestedSlice(u.Object, "status", "conditions")
+	if err != nil {
+		return HealthUnknown, "", fmt.Errorf("reading status.conditions: %w", err)
+	}
+	if !found {
+		return HealthInProgress, "waiting for status to be reported", nil
+	}
+
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		condType, _ := condition["type"].(string)
+		if condType != "Ready" && condType != "Available" {
+			continue
+		}
+
+		status, _ := condition["status"].(string)
+		if status == "True" {
+			return HealthComplete, "stabilized", nil
+		}
+
+		message, _ := condition["message"].(string)
+		return HealthInProgress, fmt.Sprintf("waiting for %s condition: %s", condType, message), nil
+	}
+
+	return HealthInProgress, "waiting for Ready/Available condition", nil
+}
+
+// argoRolloutHealthChecker checks the health of an Argo Rollout
+// (argoproj.io/Rollout), which reports its overall health as
+// `status.phase`.
+type argoRolloutHealthChecker struct{}
+
+func (argoRolloutHealthChecker) Check(u *unstructured.Unstructured) (HealthStatus, string, error) {
+	phase, found, err := unstructured.NestedString(u.Object, "status", "phase")
+	if err != nil {
+		return HealthUnknown, "", fmt.Errorf("reading status.phase: %w", err)
+	}
+	if !found {
+		return HealthInProgress, "waiting for rollout status to be reported", nil
+	}
+
+	switch phase {
+	case "Healthy":
+		return HealthComplete, "stabilized", nil
+	case "Degraded":
+		return HealthFailed, "rollout is degraded", nil
+	default:
+		return HealthInProgress, fmt.Sprintf("rollout phase is %s", phase), nil
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestConditionsHealthChecker(t *testing.T) {
+	tests := []struct {
+		description string
+		conditions  []interface{}
+		expected    HealthStatus
+		shouldErr   bool
+	}{
+		{
+			description: "Ready condition is true",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+			expected: HealthComplete,
+		},
+		{
+			description: "Ready condition is false",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "message": "still reconciling"},
+			},
+			expected: HealthInProgress,
+		},
+		{
+			description: "no conditions reported yet",
+			expected:    HealthInProgress,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if test.conditions != nil {
+				u.Object["status"] = map[string]interface{}{"conditions": test.conditions}
+			}
+
+			status, _, err := conditionsHealthChecker{}.Check(u)
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expected, status)
+		})
+	}
+}
+
+func TestArgoRolloutHealthChecker(t *testing.T) {
+	tests := []struct {
+		description string
+		phase       string
+		expected    HealthStatus
+	}{
+		{description: "healthy rollout", phase: "Healthy", expected: HealthComplete},
+		{description: "degraded rollout", phase: "Degraded", expected: HealthFailed},
+		{description: "progressing rollout", phase: "Progressing", expected: HealthInProgress},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			u := &unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{"phase": test.phase},
+			}}
+
+			status, _, err := argoRolloutHealthChecker{}.Check(u)
+			t.CheckError(false, err)
+			t.CheckDeepEqual(test.expected, status)
+		})
+	}
+}
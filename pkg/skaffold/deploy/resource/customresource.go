@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// CustomResource tracks the readiness of a labelled custom resource using a
+// HealthChecker resolved from a Registry.
+type CustomResource struct {
+	name      string
+	namespace string
+	gvr       schema.GroupVersionResource
+	checker   HealthChecker
+	status    Status
+	done      bool
+	deadline  time.Duration
+}
+
+// NewCustomResource creates a CustomResource polled through checker.
+func NewCustomResource(name, namespace string, gvr schema.GroupVersionResource, checker HealthChecker, deadline time.Duration) *CustomResource {
+	return &CustomResource{
+		name:      name,
+		namespace: namespace,
+		gvr:       gvr,
+		checker:   checker,
+		status:    newStatus("", nil),
+		deadline:  deadline,
+	}
+}
+
+// CheckStatus fetches the live object via the dynamic client and hands it
+// to the resolved HealthChecker.
+func (c *CustomResource) CheckStatus(ctx context.Context, client dynamic.Interface) {
+	u, err := client.Resource(c.gvr).Namespace(c.namespace).Get(ctx, c.name, metav1.GetOptions{})
+	if err != nil {
+		c.UpdateStatus(fmt.Sprintf("unable to check status of %s", c), err)
+		return
+	}
+
+	health, details, err := c.checker.Check(u)
+	if err != nil {
+		c.UpdateStatus("", fmt.Errorf("checking health of %s: %w", c, err))
+		c.MarkDone()
+		return
+	}
+
+	switch health {
+	case HealthComplete:
+		c.UpdateStatus(details, nil)
+		c.MarkDone()
+	case HealthFailed:
+		c.UpdateStatus("", errors.New(details))
+		c.MarkDone()
+	default:
+		c.UpdateStatus(details, nil)
+	}
+}
+
+// UpdateStatus updates the current status of the custom resource.
+func (c *CustomResource) UpdateStatus(details string, err error) {
+	updated := newStatus(details, err)
+	if !c.status.Equal(updated) {
+		c.status = updated
+	}
+}
+
+// MarkDone marks this resource as no longer needing to be polled.
+func (c *CustomResource) MarkDone() {
+	c.done = true
+}
+
+// IsStatusCheckComplete returns true if this resource's status no longer
+// needs to be polled.
+func (c *CustomResource) IsStatusCheckComplete() bool {
+	return c.done
+}
+
+// Deadline returns the duration Skaffold should wait for this resource to
+// stabilize before giving up.
+func (c *CustomResource) Deadline() time.Duration {
+	return c.deadline
+}
+
+// Status returns the last observed status of the custom resource.
+func (c *CustomResource) Status() Status {
+	return c.status
+}
+
+func (c *CustomResource) String() string {
+	kind := strings.ToLower(c.gvr.Resource)
+	if c.namespace == "default" {
+		return fmt.Sprintf("%s/%s", kind, c.name)
+	}
+	return fmt.Sprintf("%s:%s/%s", c.namespace, kind, c.name)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const daemonSetType = "daemonset"
+
+// DaemonSet tracks the rollout status of an appsv1.DaemonSet.
+type DaemonSet struct {
+	name      string
+	namespace string
+	status    Status
+	done      bool
+	deadline  time.Duration
+}
+
+// NewDaemonSet creates a DaemonSet resource with the given poll deadline.
+func NewDaemonSet(name string, namespace string, deadline time.Duration) *DaemonSet {
+	return &DaemonSet{
+		name:      name,
+		namespace: namespace,
+		status:    newStatus("", nil),
+		deadline:  deadline,
+	}
+}
+
+// CheckStatus mirrors Helm's `kube.ReadyChecker` rule for DaemonSets: the
+// controller must have observed the latest spec and enough Pods must be
+// ready to tolerate `maxUnavailable`.
+func (ds *DaemonSet) CheckStatus(ctx context.Context, client kubernetes.Interface) {
+	d, err := client.AppsV1().DaemonSets(ds.namespace).Get(ctx, ds.name, metav1.GetOptions{})
+	if err != nil {
+		ds.UpdateStatus(fmt.Sprintf("unable to check status of %s", ds), err)
+		return
+	}
+
+	if d.Status.ObservedGeneration < d.Generation {
+		ds.UpdateStatus("waiting for rollout to be observed", nil)
+		return
+	}
+
+	maxUnavailable := 0
+	if d.Spec.UpdateStrategy.RollingUpdate != nil && d.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable != nil {
+		// MaxUnavailable may be a percentage (e.g. "10%"), so it has to be
+		// scaled against DesiredNumberScheduled rather than read as a
+		// plain int - otherwise a percentage value silently becomes 0.
+		maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(d.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable, int(d.Status.DesiredNumberScheduled), true)
+		if err != nil {
+			ds.UpdateStatus(fmt.Sprintf("unable to check status of %s", ds), err)
+			return
+		}
+	}
+
+	required := int(d.Status.DesiredNumberScheduled) - maxUnavailable
+	if int(d.Status.NumberReady) < required {
+		ds.UpdateStatus(fmt.Sprintf("waiting for rollout: %d out of %d new pods ready", d.Status.NumberReady, d.Status.DesiredNumberScheduled), nil)
+		return
+	}
+
+	ds.UpdateStatus("stabilized", nil)
+	ds.MarkDone()
+}
+
+// UpdateStatus updates the current status of the daemonset.
+func (ds *DaemonSet) UpdateStatus(details string, err error) {
+	updated := newStatus(details, err)
+	if !ds.status.Equal(updated) {
+		ds.status = updated
+	}
+}
+
+// MarkDone marks this resource as no longer needing to be polled.
+func (ds *DaemonSet) MarkDone() {
+	ds.done = true
+}
+
+// IsStatusCheckComplete returns true if this resource's status no longer
+// needs to be polled.
+func (ds *DaemonSet) IsStatusCheckComplete() bool {
+	return ds.done
+}
+
+// Deadline returns the duration Skaffold should wait for this daemonset to
+// stabilize before giving up.
+func (ds *DaemonSet) Deadline() time.Duration {
+	return ds.deadline
+}
+
+// Status returns the last observed status of the daemonset.
+func (ds *DaemonSet) Status() Status {
+	return ds.status
+}
+
+func (ds *DaemonSet) String() string {
+	if ds.namespace == "default" {
+		return fmt.Sprintf("%s/%s", daemonSetType, ds.name)
+	}
+	return fmt.Sprintf("%s:%s/%s", ds.namespace, daemonSetType, ds.name)
+}
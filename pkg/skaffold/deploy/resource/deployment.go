@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const deploymentType = "deployment"
+
+// Deployment tracks the rollout status of an appsv1.Deployment.
+type Deployment struct {
+	name      string
+	namespace string
+	status    Status
+	done      bool
+	deadline  time.Duration
+}
+
+// NewDeployment creates a Deployment resource with the given poll deadline.
+func NewDeployment(name string, namespace string, deadline time.Duration) *Deployment {
+	return &Deployment{
+		name:      name,
+		namespace: namespace,
+		status:    newStatus("", nil),
+		deadline:  deadline,
+	}
+}
+
+// CheckStatus mirrors Helm's `kube.ReadyChecker` rule for Deployments: the
+// rollout is done once the controller has observed the latest spec, has
+// finished updating all replicas, and all of those replicas are available.
+func (d *Deployment) CheckStatus(ctx context.Context, client kubernetes.Interface) {
+	dep, err := client.AppsV1().Deployments(d.namespace).Get(ctx, d.name, metav1.GetOptions{})
+	if err != nil {
+		d.UpdateStatus(fmt.Sprintf("unable to check status of %s", d), err)
+		return
+	}
+
+	if dep.Status.ObservedGeneration < dep.Generation {
+		d.UpdateStatus("waiting for rollout to be observed", nil)
+		return
+	}
+
+	spec := dep.Spec
+	status := dep.Status
+	desired := int32(1)
+	if spec.Replicas != nil {
+		desired = *spec.Replicas
+	}
+
+	switch {
+	case status.UpdatedReplicas < desired:
+		d.UpdateStatus(fmt.Sprintf("waiting for rollout: %d out of %d new replicas updated", status.UpdatedReplicas, desired), nil)
+	case status.Replicas > status.UpdatedReplicas:
+		d.UpdateStatus(fmt.Sprintf("waiting for rollout: %d old replicas pending termination", status.Replicas-status.UpdatedReplicas), nil)
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		d.UpdateStatus(fmt.Sprintf("waiting for rollout: %d of %d updated replicas available", status.AvailableReplicas, status.UpdatedReplicas), nil)
+	case spec.Strategy.Type == appsv1.RollingUpdateDeploymentStrategyType && !d.podsReady(ctx, client, dep):
+		d.UpdateStatus("waiting for pods to be ready", nil)
+	default:
+		d.UpdateStatus("stabilized", nil)
+		d.MarkDone()
+	}
+}
+
+func (d *Deployment) podsReady(ctx context.Context, client kubernetes.Interface, dep *appsv1.Deployment) bool {
+	selector := metav1.FormatLabelSelector(dep.Spec.Selector)
+	pods, err := client.CoreV1().Pods(d.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil || len(pods.Items) == 0 {
+		return false
+	}
+	for _, pod := range pods.Items {
+		if !podReady(&pod) {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateStatus updates the current status of the deployment.
+func (d *Deployment) UpdateStatus(details string, err error) {
+	updated := newStatus(details, err)
+	if !d.status.Equal(updated) {
+		d.status = updated
+	}
+}
+
+// MarkDone marks this resource as no longer needing to be polled.
+func (d *Deployment) MarkDone() {
+	d.done = true
+}
+
+// IsStatusCheckComplete returns true if this resource's status no longer
+// needs to be polled.
+func (d *Deployment) IsStatusCheckComplete() bool {
+	return d.done
+}
+
+// Deadline returns the duration Skaffold should wait for this deployment to
+// stabilize before giving up.
+func (d *Deployment) Deadline() time.Duration {
+	return d.deadline
+}
+
+// Status returns the last observed status of the deployment.
+func (d *Deployment) Status() Status {
+	return d.status
+}
+
+func (d *Deployment) String() string {
+	if d.namespace == "default" {
+		return fmt.Sprintf("%s/%s", deploymentType, d.name)
+	}
+	return fmt.Sprintf("%s:%s/%s", d.namespace, deploymentType, d.name)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// HealthStatus is the readiness state a HealthChecker reports for a custom
+// resource.
+type HealthStatus int
+
+const (
+	// HealthUnknown is reported when a HealthChecker cannot make any
+	// determination about the resource, e.g. its status is malformed. The
+	// built-in checkers return it only together with an error; a status not
+	// yet populated by its controller is reported as HealthInProgress.
+	HealthUnknown HealthStatus = iota
+	// HealthInProgress is reported while the resource is still converging.
+	HealthInProgress
+	// HealthComplete is reported once the resource is ready.
+	HealthComplete
+	// HealthFailed is reported when the resource has reached a terminal
+	// error state and polling should stop.
+	HealthFailed
+)
+
+// HealthChecker inspects a single custom resource and reports its
+// readiness. Implementations interpret whatever status conventions the CRD
+// in question follows.
+type HealthChecker interface {
+	// Check returns the resource's current health, a human-readable detail
+	// message suitable for `printStatus`, and an error only if the resource
+	// could not be inspected at all (e.g. a malformed status).
+	Check(u *unstructured.Unstructured) (HealthStatus, string, error)
+}
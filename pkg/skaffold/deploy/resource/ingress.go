@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const ingressType = "ingress"
+
+// Ingress tracks whether a networkingv1.Ingress has been assigned a load
+// balancer address.
+type Ingress struct {
+	name      string
+	namespace string
+	status    Status
+	done      bool
+	deadline  time.Duration
+}
+
+// NewIngress creates an Ingress resource with the given poll deadline.
+func NewIngress(name string, namespace string, deadline time.Duration) *Ingress {
+	return &Ingress{
+		name:      name,
+		namespace: namespace,
+		status:    newStatus("", nil),
+		deadline:  deadline,
+	}
+}
+
+// CheckStatus considers an Ingress ready once its status reports at least
+// one load balancer address.
+func (i *Ingress) CheckStatus(ctx context.Context, client kubernetes.Interface) {
+	ing, err := client.NetworkingV1().Ingresses(i.namespace).Get(ctx, i.name, metav1.GetOptions{})
+	if err != nil {
+		i.UpdateStatus(fmt.Sprintf("unable to check status of %s", i), err)
+		return
+	}
+
+	if len(ing.Status.LoadBalancer.Ingress) == 0 {
+		i.UpdateStatus("waiting for load balancer to be assigned", nil)
+		return
+	}
+
+	i.UpdateStatus("stabilized", nil)
+	i.MarkDone()
+}
+
+// UpdateStatus updates the current status of the ingress.
+func (i *Ingress) UpdateStatus(details string, err error) {
+	updated := newStatus(details, err)
+	if !i.status.Equal(updated) {
+		i.status = updated
+	}
+}
+
+// MarkDone marks this resource as no longer needing to be polled.
+func (i *Ingress) MarkDone() {
+	i.done = true
+}
+
+// IsStatusCheckComplete returns true if this resource's status no longer
+// needs to be polled.
+func (i *Ingress) IsStatusCheckComplete() bool {
+	return i.done
+}
+
+// Deadline returns the duration Skaffold should wait for this ingress to
+// stabilize before giving up.
+func (i *Ingress) Deadline() time.Duration {
+	return i.deadline
+}
+
+// Status returns the last observed status of the ingress.
+func (i *Ingress) Status() Status {
+	return i.status
+}
+
+func (i *Ingress) String() string {
+	if i.namespace == "default" {
+		return fmt.Sprintf("%s/%s", ingressType, i.name)
+	}
+	return fmt.Sprintf("%s:%s/%s", i.namespace, ingressType, i.name)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const jobType = "job"
+
+// Job tracks whether a batchv1.Job has run to completion.
+type Job struct {
+	name      string
+	namespace string
+	status    Status
+	done      bool
+	deadline  time.Duration
+}
+
+// NewJob creates a Job resource with the given poll deadline.
+func NewJob(name string, namespace string, deadline time.Duration) *Job {
+	return &Job{
+		name:      name,
+		namespace: namespace,
+		status:    newStatus("", nil),
+		deadline:  deadline,
+	}
+}
+
+// CheckStatus considers a Job complete once it has a `Complete` condition or
+// at least one successful Pod.
+func (j *Job) CheckStatus(ctx context.Context, client kubernetes.Interface) {
+	job, err := client.BatchV1().Jobs(j.namespace).Get(ctx, j.name, metav1.GetOptions{})
+	if err != nil {
+		j.UpdateStatus(fmt.Sprintf("unable to check status of %s", j), err)
+		return
+	}
+
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == "True" {
+			j.UpdateStatus("stabilized", fmt.Errorf("job failed: %s", c.Message))
+			j.MarkDone()
+			return
+		}
+		if c.Type == batchv1.JobComplete && c.Status == "True" {
+			j.UpdateStatus("stabilized", nil)
+			j.MarkDone()
+			return
+		}
+	}
+
+	if job.Status.Succeeded > 0 {
+		j.UpdateStatus("stabilized", nil)
+		j.MarkDone()
+		return
+	}
+
+	j.UpdateStatus("waiting for job to complete", nil)
+}
+
+// UpdateStatus updates the current status of the job.
+func (j *Job) UpdateStatus(details string, err error) {
+	updated := newStatus(details, err)
+	if !j.status.Equal(updated) {
+		j.status = updated
+	}
+}
+
+// MarkDone marks this resource as no longer needing to be polled.
+func (j *Job) MarkDone() {
+	j.done = true
+}
+
+// IsStatusCheckComplete returns true if this resource's status no longer
+// needs to be polled.
+func (j *Job) IsStatusCheckComplete() bool {
+	return j.done
+}
+
+// Deadline returns the duration Skaffold should wait for this job to
+// complete before giving up.
+func (j *Job) Deadline() time.Duration {
+	return j.deadline
+}
+
+// Status returns the last observed status of the job.
+func (j *Job) Status() Status {
+	return j.status
+}
+
+func (j *Job) String() string {
+	if j.namespace == "default" {
+		return fmt.Sprintf("%s/%s", jobType, j.name)
+	}
+	return fmt.Sprintf("%s:%s/%s", j.namespace, jobType, j.name)
+}
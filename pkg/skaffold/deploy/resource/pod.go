@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const podType = "pod"
+
+// Pod tracks whether a corev1.Pod and all of its containers are ready.
+type Pod struct {
+	name      string
+	namespace string
+	status    Status
+	done      bool
+	deadline  time.Duration
+}
+
+// NewPod creates a Pod resource with the given poll deadline.
+func NewPod(name string, namespace string, deadline time.Duration) *Pod {
+	return &Pod{
+		name:      name,
+		namespace: namespace,
+		status:    newStatus("", nil),
+		deadline:  deadline,
+	}
+}
+
+// CheckStatus considers a Pod ready once its `Ready` condition is true,
+// which implies every container in the Pod is ready.
+func (p *Pod) CheckStatus(ctx context.Context, client kubernetes.Interface) {
+	pod, err := client.CoreV1().Pods(p.namespace).Get(ctx, p.name, metav1.GetOptions{})
+	if err != nil {
+		p.UpdateStatus(fmt.Sprintf("unable to check status of %s", p), err)
+		return
+	}
+
+	if pod.Status.Phase == corev1.PodFailed {
+		p.UpdateStatus("stabilized", fmt.Errorf("pod failed: %s", pod.Status.Reason))
+		p.MarkDone()
+		return
+	}
+
+	if !podReady(pod) {
+		p.UpdateStatus(fmt.Sprintf("waiting for pod to be ready, phase is %s", pod.Status.Phase), nil)
+		return
+	}
+
+	p.UpdateStatus("stabilized", nil)
+	p.MarkDone()
+}
+
+// podReady reports whether the Pod's `Ready` condition is true, which the
+// kubelet only sets once every container in the Pod is ready.
+func podReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// UpdateStatus updates the current status of the pod.
+func (p *Pod) UpdateStatus(details string, err error) {
+	updated := newStatus(details, err)
+	if !p.status.Equal(updated) {
+		p.status = updated
+	}
+}
+
+// MarkDone marks this resource as no longer needing to be polled.
+func (p *Pod) MarkDone() {
+	p.done = true
+}
+
+// IsStatusCheckComplete returns true if this resource's status no longer
+// needs to be polled.
+func (p *Pod) IsStatusCheckComplete() bool {
+	return p.done
+}
+
+// Deadline returns the duration Skaffold should wait for this pod to become
+// ready before giving up.
+func (p *Pod) Deadline() time.Duration {
+	return p.deadline
+}
+
+// Status returns the last observed status of the pod.
+func (p *Pod) Status() Status {
+	return p.status
+}
+
+func (p *Pod) String() string {
+	if p.namespace == "default" {
+		return fmt.Sprintf("%s/%s", podType, p.name)
+	}
+	return fmt.Sprintf("%s:%s/%s", p.namespace, podType, p.name)
+}
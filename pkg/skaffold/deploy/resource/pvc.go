@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const pvcType = "persistentvolumeclaim"
+
+// PersistentVolumeClaim tracks whether a corev1.PersistentVolumeClaim has
+// been bound to a volume.
+type PersistentVolumeClaim struct {
+	name      string
+	namespace string
+	status    Status
+	done      bool
+	deadline  time.Duration
+}
+
+// NewPersistentVolumeClaim creates a PersistentVolumeClaim resource with the
+// given poll deadline.
+func NewPersistentVolumeClaim(name string, namespace string, deadline time.Duration) *PersistentVolumeClaim {
+	return &PersistentVolumeClaim{
+		name:      name,
+		namespace: namespace,
+		status:    newStatus("", nil),
+		deadline:  deadline,
+	}
+}
+
+// CheckStatus considers a PersistentVolumeClaim ready once it has reached
+// the Bound phase.
+func (p *PersistentVolumeClaim) CheckStatus(ctx context.Context, client kubernetes.Interface) {
+	pvc, err := client.CoreV1().PersistentVolumeClaims(p.namespace).Get(ctx, p.name, metav1.GetOptions{})
+	if err != nil {
+		p.UpdateStatus(fmt.Sprintf("unable to check status of %s", p), err)
+		return
+	}
+
+	if pvc.Status.Phase != corev1.ClaimBound {
+		p.UpdateStatus(fmt.Sprintf("waiting for claim to be bound, phase is %s", pvc.Status.Phase), nil)
+		return
+	}
+
+	p.UpdateStatus("stabilized", nil)
+	p.MarkDone()
+}
+
+// UpdateStatus updates the current status of the claim.
+func (p *PersistentVolumeClaim) UpdateStatus(details string, err error) {
+	updated := newStatus(details, err)
+	if !p.status.Equal(updated) {
+		p.status = updated
+	}
+}
+
+// MarkDone marks this resource as no longer needing to be polled.
+func (p *PersistentVolumeClaim) MarkDone() {
+	p.done = true
+}
+
+// IsStatusCheckComplete returns true if this resource's status no longer
+// needs to be polled.
+func (p *PersistentVolumeClaim) IsStatusCheckComplete() bool {
+	return p.done
+}
+
+// Deadline returns the duration Skaffold should wait for this claim to be
+// bound before giving up.
+func (p *PersistentVolumeClaim) Deadline() time.Duration {
+	return p.deadline
+}
+
+// Status returns the last observed status of the claim.
+func (p *PersistentVolumeClaim) Status() Status {
+	return p.status
+}
+
+func (p *PersistentVolumeClaim) String() string {
+	if p.namespace == "default" {
+		return fmt.Sprintf("%s/%s", pvcType, p.name)
+	}
+	return fmt.Sprintf("%s:%s/%s", p.namespace, pvcType, p.name)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Registry maps the GroupKind of a custom resource to the HealthChecker
+// that knows how to interpret its status conventions.
+type Registry struct {
+	mu       sync.RWMutex
+	checkers map[schema.GroupKind]HealthChecker
+}
+
+// NewRegistry creates a Registry pre-populated with the HealthCheckers
+// Skaffold ships out of the box.
+func NewRegistry() *Registry {
+	r := &Registry{checkers: map[schema.GroupKind]HealthChecker{}}
+
+	r.RegisterHealthCheck(schema.GroupKind{Group: "argoproj.io", Kind: "Rollout"}, argoRolloutHealthChecker{})
+	r.RegisterHealthCheck(schema.GroupKind{Group: "serving.knative.dev", Kind: "Service"}, conditionsHealthChecker{})
+	r.RegisterHealthCheck(schema.GroupKind{Group: "cert-manager.io", Kind: "Certificate"}, conditionsHealthChecker{})
+
+	return r
+}
+
+// RegisterHealthCheck registers checker as the HealthChecker for gk,
+// overriding any previously registered checker, including the built-ins.
+// Integrations use this hook to teach Skaffold about site-specific CRDs.
+func (r *Registry) RegisterHealthCheck(gk schema.GroupKind, checker HealthChecker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers[gk] = checker
+}
+
+// CheckerFor returns the HealthChecker registered for gk, falling back to a
+// generic `status.conditions`-based checker when none is registered.
+func (r *Registry) CheckerFor(gk schema.GroupKind) HealthChecker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if checker, ok := r.checkers[gk]; ok {
+		return checker
+	}
+	return conditionsHealthChecker{}
+}
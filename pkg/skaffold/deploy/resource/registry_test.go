@@ -0,0 +1,49 @@
+/*
+Copyright 2020 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestRegistryCheckerFor(t *testing.T) {
+	testutil.Run(t, "falls back to the generic checker for unregistered GroupKinds", func(t *testutil.T) {
+		rolloutGK := schema.GroupKind{Group: "argoproj.io", Kind: "Rollout"}
+		unknownGK := schema.GroupKind{Group: "example.com", Kind: "Widget"}
+
+		r := NewRegistry()
+
+		t.CheckDeepEqual(argoRolloutHealthChecker{}, r.CheckerFor(rolloutGK))
+		t.CheckDeepEqual(conditionsHealthChecker{}, r.CheckerFor(unknownGK))
+	})
+}
+
+func TestRegistryRegisterHealthCheckOverridesBuiltin(t *testing.T) {
+	testutil.Run(t, "RegisterHealthCheck overrides a built-in checker", func(t *testutil.T) {
+		knativeServiceGK := schema.GroupKind{Group: "serving.knative.dev", Kind: "Service"}
+		custom := argoRolloutHealthChecker{}
+
+		r := NewRegistry()
+		r.RegisterHealthCheck(knativeServiceGK, custom)
+
+		t.CheckDeepEqual(custom, r.CheckerFor(knativeServiceGK))
+	})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const serviceType = "service"
+
+// Service tracks whether a corev1.Service has been assigned an address.
+type Service struct {
+	name      string
+	namespace string
+	status    Status
+	done      bool
+	deadline  time.Duration
+}
+
+// NewService creates a Service resource with the given poll deadline.
+func NewService(name string, namespace string, deadline time.Duration) *Service {
+	return &Service{
+		name:      name,
+		namespace: namespace,
+		status:    newStatus("", nil),
+		deadline:  deadline,
+	}
+}
+
+// CheckStatus considers a Service ready once it has a ClusterIP, or, for a
+// LoadBalancer Service, once the load balancer has been provisioned.
+func (s *Service) CheckStatus(ctx context.Context, client kubernetes.Interface) {
+	svc, err := client.CoreV1().Services(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		s.UpdateStatus(fmt.Sprintf("unable to check status of %s", s), err)
+		return
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		if len(svc.Status.LoadBalancer.Ingress) == 0 {
+			s.UpdateStatus("waiting for load balancer to be assigned", nil)
+			return
+		}
+		s.UpdateStatus("stabilized", nil)
+		s.MarkDone()
+		return
+	}
+
+	if svc.Spec.ClusterIP == "" {
+		s.UpdateStatus("waiting for cluster IP to be assigned", nil)
+		return
+	}
+
+	s.UpdateStatus("stabilized", nil)
+	s.MarkDone()
+}
+
+// UpdateStatus updates the current status of the service.
+func (s *Service) UpdateStatus(details string, err error) {
+	updated := newStatus(details, err)
+	if !s.status.Equal(updated) {
+		s.status = updated
+	}
+}
+
+// MarkDone marks this resource as no longer needing to be polled.
+func (s *Service) MarkDone() {
+	s.done = true
+}
+
+// IsStatusCheckComplete returns true if this resource's status no longer
+// needs to be polled.
+func (s *Service) IsStatusCheckComplete() bool {
+	return s.done
+}
+
+// Deadline returns the duration Skaffold should wait for this service to
+// stabilize before giving up.
+func (s *Service) Deadline() time.Duration {
+	return s.deadline
+}
+
+// Status returns the last observed status of the service.
+func (s *Service) Status() Status {
+	return s.status
+}
+
+func (s *Service) String() string {
+	if s.namespace == "default" {
+		return fmt.Sprintf("%s/%s", serviceType, s.name)
+	}
+	return fmt.Sprintf("%s:%s/%s", s.namespace, serviceType, s.name)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const statefulSetType = "statefulset"
+
+// StatefulSet tracks the rollout status of an appsv1.StatefulSet.
+type StatefulSet struct {
+	name      string
+	namespace string
+	status    Status
+	done      bool
+	deadline  time.Duration
+}
+
+// NewStatefulSet creates a StatefulSet resource with the given poll deadline.
+func NewStatefulSet(name string, namespace string, deadline time.Duration) *StatefulSet {
+	return &StatefulSet{
+		name:      name,
+		namespace: namespace,
+		status:    newStatus("", nil),
+		deadline:  deadline,
+	}
+}
+
+// CheckStatus mirrors Helm's `kube.ReadyChecker` rule for StatefulSets: the
+// controller must have observed the latest spec, every replica must be
+// ready, and - unless a partitioned rolling update is in progress - the
+// current and update revisions must match.
+func (s *StatefulSet) CheckStatus(ctx context.Context, client kubernetes.Interface) {
+	sts, err := client.AppsV1().StatefulSets(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		s.UpdateStatus(fmt.Sprintf("unable to check status of %s", s), err)
+		return
+	}
+
+	if sts.Status.ObservedGeneration < sts.Generation {
+		s.UpdateStatus("waiting for rollout to be observed", nil)
+		return
+	}
+
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+
+	if sts.Status.ReadyReplicas < desired {
+		s.UpdateStatus(fmt.Sprintf("waiting for rollout: %d out of %d replicas ready", sts.Status.ReadyReplicas, desired), nil)
+		return
+	}
+
+	partition := int32(0)
+	if sts.Spec.UpdateStrategy.RollingUpdate != nil && sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		partition = *sts.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+
+	if partition == 0 {
+		if sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+			s.UpdateStatus("waiting for statefulset rolling update to complete", nil)
+			return
+		}
+	} else if sts.Status.UpdatedReplicas < desired-partition {
+		s.UpdateStatus(fmt.Sprintf("waiting for partitioned roll out: %d out of %d replicas updated", sts.Status.UpdatedReplicas, desired-partition), nil)
+		return
+	}
+
+	s.UpdateStatus("stabilized", nil)
+	s.MarkDone()
+}
+
+// UpdateStatus updates the current status of the statefulset.
+func (s *StatefulSet) UpdateStatus(details string, err error) {
+	updated := newStatus(details, err)
+	if !s.status.Equal(updated) {
+		s.status = updated
+	}
+}
+
+// MarkDone marks this resource as no longer needing to be polled.
+func (s *StatefulSet) MarkDone() {
+	s.done = true
+}
+
+// IsStatusCheckComplete returns true if this resource's status no longer
+// needs to be polled.
+func (s *StatefulSet) IsStatusCheckComplete() bool {
+	return s.done
+}
+
+// Deadline returns the duration Skaffold should wait for this statefulset to
+// stabilize before giving up.
+func (s *StatefulSet) Deadline() time.Duration {
+	return s.deadline
+}
+
+// Status returns the last observed status of the statefulset.
+func (s *StatefulSet) Status() Status {
+	return s.status
+}
+
+func (s *StatefulSet) String() string {
+	if s.namespace == "default" {
+		return fmt.Sprintf("%s/%s", statefulSetType, s.name)
+	}
+	return fmt.Sprintf("%s:%s/%s", s.namespace, statefulSetType, s.name)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+// Status captures the last observed state of a Resource being polled by the
+// status checker.
+type Status struct {
+	details string
+	err     error
+}
+
+func newStatus(details string, err error) Status {
+	return Status{
+		details: details,
+		err:     err,
+	}
+}
+
+// NewStatus creates a Status from details and err. It's exported so a
+// Resource implementation defined outside this package - e.g. for a
+// Kubernetes kind Skaffold has no built-in readiness checker for - can
+// still report status through the same type status_check.go prints and
+// aggregates errors from.
+func NewStatus(details string, err error) Status {
+	return newStatus(details, err)
+}
+
+// Error returns the error associated with this status, if any.
+func (rs Status) Error() error {
+	return rs.err
+}
+
+// Equal returns true if the two statuses represent the same observation.
+func (rs Status) Equal(other Status) bool {
+	if rs.details != other.details {
+		return false
+	}
+	if rs.err == nil || other.err == nil {
+		return rs.err == other.err
+	}
+	return rs.err.Error() == other.err.Error()
+}
+
+func (rs Status) String() string {
+	if rs.err != nil {
+		return rs.err.Error()
+	}
+	return rs.details
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/resource"
+)
+
+// getResources enumerates every kind Skaffold knows how to status-check -
+// the built-in Deployments, StatefulSets, DaemonSets, Services, Ingresses,
+// PersistentVolumeClaims, Jobs and Pods, plus any labelled custom resource
+// outside builtinGroups, registered resource.HealthChecker or not - that
+// were deployed during this run.
+func getResources(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface, registry *resource.Registry, ns string, l *Labeller, defaultDeadline time.Duration) ([]Resource, error) {
+	deployments, err := getDeployments(client, ns, l, defaultDeadline)
+	if err != nil {
+		return nil, err
+	}
+	statefulSets, err := getStatefulSets(ctx, client, ns, l, defaultDeadline)
+	if err != nil {
+		return nil, err
+	}
+	daemonSets, err := getDaemonSets(ctx, client, ns, l, defaultDeadline)
+	if err != nil {
+		return nil, err
+	}
+	services, err := getServices(ctx, client, ns, l, defaultDeadline)
+	if err != nil {
+		return nil, err
+	}
+	ingresses, err := getIngresses(ctx, client, ns, l, defaultDeadline)
+	if err != nil {
+		return nil, err
+	}
+	pvcs, err := getPersistentVolumeClaims(ctx, client, ns, l, defaultDeadline)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := getJobs(ctx, client, ns, l, defaultDeadline)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := getPods(ctx, client, ns, l, defaultDeadline)
+	if err != nil {
+		return nil, err
+	}
+	customResources, err := getCustomResources(ctx, dynamicClient, discoveryClient, registry, ns, l, defaultDeadline)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	for _, d := range deployments {
+		resources = append(resources, d)
+	}
+	for _, s := range statefulSets {
+		resources = append(resources, s)
+	}
+	for _, ds := range daemonSets {
+		resources = append(resources, ds)
+	}
+	for _, svc := range services {
+		resources = append(resources, svc)
+	}
+	for _, ing := range ingresses {
+		resources = append(resources, ing)
+	}
+	for _, pvc := range pvcs {
+		resources = append(resources, pvc)
+	}
+	for _, j := range jobs {
+		resources = append(resources, j)
+	}
+	for _, p := range pods {
+		resources = append(resources, p)
+	}
+	for _, cr := range customResources {
+		resources = append(resources, cr)
+	}
+
+	return resources, nil
+}
+
+// builtinGroups are the API groups getResources already polls directly
+// through typed clients (Deployments, StatefulSets, DaemonSets, Services,
+// Ingresses, PVCs, Jobs, Pods). getCustomResources only considers resources
+// outside these so built-ins aren't discovered and checked a second time
+// through the dynamic client.
+var builtinGroups = map[string]bool{
+	"":                  true, // core: Services, PersistentVolumeClaims, Pods
+	"apps":              true, // Deployments, StatefulSets, DaemonSets
+	"batch":             true, // Jobs
+	"networking.k8s.io": true, // Ingresses
+}
+
+// getCustomResources discovers every namespaced, listable resource the
+// cluster serves outside builtinGroups, lists the ones labelled as deployed
+// during this run, and picks a resource.HealthChecker for each via registry
+// - falling back to registry's generic conditions-based checker for
+// GroupKinds nobody has registered one for. Discovery is driven entirely by
+// what's actually in the cluster, not by what's in registry, so an
+// arbitrary CRD a user deploys is observed even before anyone has called
+// RegisterHealthCheck for it.
+func getCustomResources(ctx context.Context, dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface, registry *resource.Registry, ns string, l *Labeller, defaultDeadline time.Duration) ([]*resource.CustomResource, error) {
+	kinds, err := discoverCustomResourceKinds(discoveryClient)
+	if err != nil {
+		return nil, fmt.Errorf("discovering custom resources: %w", err)
+	}
+
+	var customResources []*resource.CustomResource
+	for _, k := range kinds {
+		list, err := dynamicClient.Resource(k.gvr).Namespace(ns).List(ctx, metav1.ListOptions{LabelSelector: l.RunIDSelector()})
+		if err != nil {
+			// Not every discovered resource is necessarily listable here
+			// (e.g. RBAC scoped to specific CRDs); skip it rather than
+			// failing the whole deploy over an unrelated resource kind.
+			continue
+		}
+
+		checker := registry.CheckerFor(k.groupKind)
+		for _, u := range list.Items {
+			u := u
+			customResources = append(customResources, resource.NewCustomResource(u.GetName(), u.GetNamespace(), k.gvr, checker, defaultDeadline))
+		}
+	}
+
+	return customResources, nil
+}
+
+// customResourceKind pairs the GroupVersionResource used to list a kind
+// with the GroupKind used to look up its HealthChecker.
+type customResourceKind struct {
+	gvr       schema.GroupVersionResource
+	groupKind schema.GroupKind
+}
+
+// discoverCustomResourceKinds lists every namespaced, listable resource the
+// cluster serves outside builtinGroups.
+func discoverCustomResourceKinds(discoveryClient discovery.DiscoveryInterface) ([]customResourceKind, error) {
+	groups, err := discovery.ServerPreferredResources(discoveryClient)
+	if err != nil && len(groups) == 0 {
+		return nil, err
+	}
+
+	var kinds []customResourceKind
+	for _, group := range groups {
+		gv, err := schema.ParseGroupVersion(group.GroupVersion)
+		if err != nil || builtinGroups[gv.Group] {
+			continue
+		}
+		for _, apiResource := range group.APIResources {
+			if !apiResource.Namespaced || !containsVerb(apiResource.Verbs, "list") {
+				continue
+			}
+			kinds = append(kinds, customResourceKind{
+				gvr:       gv.WithResource(apiResource.Name),
+				groupKind: schema.GroupKind{Group: gv.Group, Kind: apiResource.Kind},
+			})
+		}
+	}
+	return kinds, nil
+}
+
+func containsVerb(verbs metav1.Verbs, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+func getStatefulSets(ctx context.Context, client kubernetes.Interface, ns string, l *Labeller, defaultDeadline time.Duration) ([]*resource.StatefulSet, error) {
+	list, err := client.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{LabelSelector: l.RunIDSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("listing statefulsets: %w", err)
+	}
+
+	statefulSets := make([]*resource.StatefulSet, 0, len(list.Items))
+	for _, s := range list.Items {
+		statefulSets = append(statefulSets, resource.NewStatefulSet(s.Name, s.Namespace, defaultDeadline))
+	}
+	return statefulSets, nil
+}
+
+func getDaemonSets(ctx context.Context, client kubernetes.Interface, ns string, l *Labeller, defaultDeadline time.Duration) ([]*resource.DaemonSet, error) {
+	list, err := client.AppsV1().DaemonSets(ns).List(ctx, metav1.ListOptions{LabelSelector: l.RunIDSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("listing daemonsets: %w", err)
+	}
+
+	daemonSets := make([]*resource.DaemonSet, 0, len(list.Items))
+	for _, d := range list.Items {
+		daemonSets = append(daemonSets, resource.NewDaemonSet(d.Name, d.Namespace, defaultDeadline))
+	}
+	return daemonSets, nil
+}
+
+func getServices(ctx context.Context, client kubernetes.Interface, ns string, l *Labeller, defaultDeadline time.Duration) ([]*resource.Service, error) {
+	list, err := client.CoreV1().Services(ns).List(ctx, metav1.ListOptions{LabelSelector: l.RunIDSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("listing services: %w", err)
+	}
+
+	services := make([]*resource.Service, 0, len(list.Items))
+	for _, s := range list.Items {
+		services = append(services, resource.NewService(s.Name, s.Namespace, defaultDeadline))
+	}
+	return services, nil
+}
+
+func getIngresses(ctx context.Context, client kubernetes.Interface, ns string, l *Labeller, defaultDeadline time.Duration) ([]*resource.Ingress, error) {
+	list, err := client.NetworkingV1().Ingresses(ns).List(ctx, metav1.ListOptions{LabelSelector: l.RunIDSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("listing ingresses: %w", err)
+	}
+
+	ingresses := make([]*resource.Ingress, 0, len(list.Items))
+	for _, i := range list.Items {
+		ingresses = append(ingresses, resource.NewIngress(i.Name, i.Namespace, defaultDeadline))
+	}
+	return ingresses, nil
+}
+
+func getPersistentVolumeClaims(ctx context.Context, client kubernetes.Interface, ns string, l *Labeller, defaultDeadline time.Duration) ([]*resource.PersistentVolumeClaim, error) {
+	list, err := client.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{LabelSelector: l.RunIDSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("listing persistentvolumeclaims: %w", err)
+	}
+
+	pvcs := make([]*resource.PersistentVolumeClaim, 0, len(list.Items))
+	for _, p := range list.Items {
+		pvcs = append(pvcs, resource.NewPersistentVolumeClaim(p.Name, p.Namespace, defaultDeadline))
+	}
+	return pvcs, nil
+}
+
+func getJobs(ctx context.Context, client kubernetes.Interface, ns string, l *Labeller, defaultDeadline time.Duration) ([]*resource.Job, error) {
+	list, err := client.BatchV1().Jobs(ns).List(ctx, metav1.ListOptions{LabelSelector: l.RunIDSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("listing jobs: %w", err)
+	}
+
+	jobs := make([]*resource.Job, 0, len(list.Items))
+	for _, j := range list.Items {
+		jobs = append(jobs, resource.NewJob(j.Name, j.Namespace, defaultDeadline))
+	}
+	return jobs, nil
+}
+
+func getPods(ctx context.Context, client kubernetes.Interface, ns string, l *Labeller, defaultDeadline time.Duration) ([]*resource.Pod, error) {
+	list, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: l.RunIDSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+
+	pods := make([]*resource.Pod, 0, len(list.Items))
+	for _, p := range list.Items {
+		pods = append(pods, resource.NewPod(p.Name, p.Namespace, defaultDeadline))
+	}
+	return pods, nil
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/resource"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestGetStatefulSets(t *testing.T) {
+	labeller := NewLabeller("")
+	testutil.Run(t, "statefulsets deployed by this run are returned", func(t *testutil.T) {
+		client := fakekubeclientset.NewSimpleClientset(
+			&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sts1",
+					Namespace: "test",
+					Labels:    map[string]string{RunIDLabel: labeller.runID},
+				},
+			},
+			&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sts2",
+					Namespace: "test",
+					Labels:    map[string]string{RunIDLabel: "9876-6789"},
+				},
+			},
+		)
+
+		actual, err := getStatefulSets(context.Background(), client, "test", labeller, time.Duration(200)*time.Second)
+
+		expected := []*resource.StatefulSet{resource.NewStatefulSet("sts1", "test", time.Duration(200)*time.Second)}
+		t.CheckErrorAndDeepEqual(false, err, &expected, &actual, cmp.AllowUnexported(resource.StatefulSet{}, resource.Status{}))
+	})
+}
+
+func TestGetDaemonSets(t *testing.T) {
+	labeller := NewLabeller("")
+	testutil.Run(t, "daemonsets deployed by this run are returned", func(t *testutil.T) {
+		client := fakekubeclientset.NewSimpleClientset(&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ds1",
+				Namespace: "test",
+				Labels:    map[string]string{RunIDLabel: labeller.runID},
+			},
+		})
+
+		actual, err := getDaemonSets(context.Background(), client, "test", labeller, time.Duration(200)*time.Second)
+
+		expected := []*resource.DaemonSet{resource.NewDaemonSet("ds1", "test", time.Duration(200)*time.Second)}
+		t.CheckErrorAndDeepEqual(false, err, &expected, &actual, cmp.AllowUnexported(resource.DaemonSet{}, resource.Status{}))
+	})
+}
+
+func TestGetServices(t *testing.T) {
+	labeller := NewLabeller("")
+	testutil.Run(t, "services deployed by this run are returned", func(t *testutil.T) {
+		client := fakekubeclientset.NewSimpleClientset(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "svc1",
+				Namespace: "test",
+				Labels:    map[string]string{RunIDLabel: labeller.runID},
+			},
+		})
+
+		actual, err := getServices(context.Background(), client, "test", labeller, time.Duration(200)*time.Second)
+
+		expected := []*resource.Service{resource.NewService("svc1", "test", time.Duration(200)*time.Second)}
+		t.CheckErrorAndDeepEqual(false, err, &expected, &actual, cmp.AllowUnexported(resource.Service{}, resource.Status{}))
+	})
+}
+
+func TestGetIngresses(t *testing.T) {
+	labeller := NewLabeller("")
+	testutil.Run(t, "ingresses deployed by this run are returned", func(t *testutil.T) {
+		client := fakekubeclientset.NewSimpleClientset(&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ing1",
+				Namespace: "test",
+				Labels:    map[string]string{RunIDLabel: labeller.runID},
+			},
+		})
+
+		actual, err := getIngresses(context.Background(), client, "test", labeller, time.Duration(200)*time.Second)
+
+		expected := []*resource.Ingress{resource.NewIngress("ing1", "test", time.Duration(200)*time.Second)}
+		t.CheckErrorAndDeepEqual(false, err, &expected, &actual, cmp.AllowUnexported(resource.Ingress{}, resource.Status{}))
+	})
+}
+
+func TestGetPersistentVolumeClaims(t *testing.T) {
+	labeller := NewLabeller("")
+	testutil.Run(t, "pvcs deployed by this run are returned", func(t *testutil.T) {
+		client := fakekubeclientset.NewSimpleClientset(&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pvc1",
+				Namespace: "test",
+				Labels:    map[string]string{RunIDLabel: labeller.runID},
+			},
+		})
+
+		actual, err := getPersistentVolumeClaims(context.Background(), client, "test", labeller, time.Duration(200)*time.Second)
+
+		expected := []*resource.PersistentVolumeClaim{resource.NewPersistentVolumeClaim("pvc1", "test", time.Duration(200)*time.Second)}
+		t.CheckErrorAndDeepEqual(false, err, &expected, &actual, cmp.AllowUnexported(resource.PersistentVolumeClaim{}, resource.Status{}))
+	})
+}
+
+func TestGetJobs(t *testing.T) {
+	labeller := NewLabeller("")
+	testutil.Run(t, "jobs deployed by this run are returned", func(t *testutil.T) {
+		client := fakekubeclientset.NewSimpleClientset(&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job1",
+				Namespace: "test",
+				Labels:    map[string]string{RunIDLabel: labeller.runID},
+			},
+		})
+
+		actual, err := getJobs(context.Background(), client, "test", labeller, time.Duration(200)*time.Second)
+
+		expected := []*resource.Job{resource.NewJob("job1", "test", time.Duration(200)*time.Second)}
+		t.CheckErrorAndDeepEqual(false, err, &expected, &actual, cmp.AllowUnexported(resource.Job{}, resource.Status{}))
+	})
+}
+
+func TestGetPods(t *testing.T) {
+	labeller := NewLabeller("")
+	testutil.Run(t, "pods deployed by this run are returned", func(t *testutil.T) {
+		client := fakekubeclientset.NewSimpleClientset(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod1",
+				Namespace: "test",
+				Labels:    map[string]string{RunIDLabel: labeller.runID},
+			},
+		})
+
+		actual, err := getPods(context.Background(), client, "test", labeller, time.Duration(200)*time.Second)
+
+		expected := []*resource.Pod{resource.NewPod("pod1", "test", time.Duration(200)*time.Second)}
+		t.CheckErrorAndDeepEqual(false, err, &expected, &actual, cmp.AllowUnexported(resource.Pod{}, resource.Status{}))
+	})
+}
+
+func TestDiscoverCustomResourceKinds(t *testing.T) {
+	testutil.Run(t, "built-in groups and non-listable resources are excluded", func(t *testutil.T) {
+		client := fakekubeclientset.NewSimpleClientset()
+		client.Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: "v1",
+				APIResources: []metav1.APIResource{
+					{Name: "pods", Namespaced: true, Kind: "Pod", Verbs: metav1.Verbs{"list"}},
+				},
+			},
+			{
+				GroupVersion: "example.com/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "widgets", Namespaced: true, Kind: "Widget", Verbs: metav1.Verbs{"list", "get"}},
+					{Name: "widgets/status", Namespaced: true, Kind: "Widget", Verbs: metav1.Verbs{"get"}},
+					{Name: "gadgets", Namespaced: false, Kind: "Gadget", Verbs: metav1.Verbs{"list"}},
+				},
+			},
+		}
+
+		actual, err := discoverCustomResourceKinds(client.Discovery())
+
+		expected := []customResourceKind{
+			{
+				gvr:       schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"},
+				groupKind: schema.GroupKind{Group: "example.com", Kind: "Widget"},
+			},
+		}
+		t.CheckErrorAndDeepEqual(false, err, &expected, &actual, cmp.AllowUnexported(customResourceKind{}))
+	})
+}
+
+func TestGetCustomResources(t *testing.T) {
+	labeller := NewLabeller("")
+	testutil.Run(t, "labelled custom resources outside builtin groups are discovered and checked via the registry", func(t *testutil.T) {
+		client := fakekubeclientset.NewSimpleClientset()
+		client.Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: "example.com/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "widgets", Namespaced: true, Kind: "Widget", Verbs: metav1.Verbs{"list"}},
+				},
+			},
+		}
+		widgetGVR := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+		matched := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata": map[string]interface{}{
+				"name":      "widget1",
+				"namespace": "test",
+				"labels":    map[string]interface{}{RunIDLabel: labeller.runID},
+			},
+		}}
+		unmatched := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata": map[string]interface{}{
+				"name":      "widget2",
+				"namespace": "test",
+				"labels":    map[string]interface{}{RunIDLabel: "9876-6789"},
+			},
+		}}
+		dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+			runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{widgetGVR: "WidgetList"},
+			matched, unmatched,
+		)
+
+		registry := resource.NewRegistry()
+		actual, err := getCustomResources(context.Background(), dynamicClient, client.Discovery(), registry, "test", labeller, time.Duration(200)*time.Second)
+
+		widgetGK := schema.GroupKind{Group: "example.com", Kind: "Widget"}
+		expected := []*resource.CustomResource{
+			resource.NewCustomResource("widget1", "test", widgetGVR, registry.CheckerFor(widgetGK), time.Duration(200)*time.Second),
+		}
+		t.CheckErrorAndDeepEqual(false, err, &expected, &actual, cmp.AllowUnexported(resource.CustomResource{}, resource.Status{}))
+	})
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/resource"
+)
+
+// Resource is anything Skaffold can poll for readiness as part of
+// `skaffold deploy --status-check`.
+type Resource interface {
+	Deadline() time.Duration
+	UpdateStatus(details string, err error)
+	Status() resource.Status
+	MarkDone()
+	IsStatusCheckComplete() bool
+	String() string
+}
+
+// StatusCheck polls every resource deployed during this run - Deployments,
+// StatefulSets, DaemonSets, Services, Ingresses, PersistentVolumeClaims,
+// Jobs, Pods, and any labelled custom resource, checked through its
+// registered resource.HealthChecker or the registry's generic fallback -
+// concurrently, until they all stabilize or their deadline elapses.
+func StatusCheck(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface, registry *resource.Registry, namespace string, defaultLabeller *Labeller, deadline time.Duration, out io.Writer) error {
+	resources, err := getResources(ctx, client, dynamicClient, discoveryClient, registry, namespace, defaultLabeller, deadline)
+	if err != nil {
+		return fmt.Errorf("getting resources: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, r := range resources {
+		wg.Add(1)
+		go func(r Resource) {
+			defer wg.Done()
+			pollUntilDone(ctx, client, dynamicClient, r)
+		}(r)
+	}
+	wg.Wait()
+
+	printStatus(resources, out)
+	return getSkaffoldDeployStatus(resources)
+}
+
+// pollUntilDone repeatedly checks a resource's status until it reports
+// itself done or its deadline elapses.
+func pollUntilDone(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, r Resource) {
+	check := resolveChecker(client, dynamicClient, r)
+	if check == nil {
+		// Kinds Skaffold doesn't know how to inspect are considered ready
+		// as soon as they're created.
+		r.UpdateStatus("stabilized", nil)
+		r.MarkDone()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.Deadline())
+	defer cancel()
+
+	pollDuration := 1 * time.Second
+	for {
+		check(ctx)
+		if r.IsStatusCheckComplete() {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			r.UpdateStatus("", fmt.Errorf("could not stabilize within %v", r.Deadline()))
+			r.MarkDone()
+			return
+		case <-time.After(pollDuration):
+		}
+	}
+}
+
+// resolveChecker binds r's CheckStatus method, if it has one, to whichever
+// of the typed or dynamic client it expects, so pollUntilDone can treat
+// every kind of Resource uniformly.
+func resolveChecker(client kubernetes.Interface, dynamicClient dynamic.Interface, r Resource) func(ctx context.Context) {
+	if checker, ok := r.(interface {
+		CheckStatus(ctx context.Context, client kubernetes.Interface)
+	}); ok {
+		return func(ctx context.Context) { checker.CheckStatus(ctx, client) }
+	}
+	if checker, ok := r.(interface {
+		CheckStatus(ctx context.Context, client dynamic.Interface)
+	}); ok {
+		return func(ctx context.Context) { checker.CheckStatus(ctx, dynamicClient) }
+	}
+	return nil
+}
+
+func getDeployments(client kubernetes.Interface, ns string, l *Labeller, defaultDeadline time.Duration) ([]*resource.Deployment, error) {
+	deps, err := client.AppsV1().Deployments(ns).List(context.Background(), metav1.ListOptions{LabelSelector: l.RunIDSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+
+	deployments := make([]*resource.Deployment, 0, len(deps.Items))
+	for _, d := range deps.Items {
+		deadline := defaultDeadline
+		if d.Spec.ProgressDeadlineSeconds != nil {
+			specDeadline := time.Duration(*d.Spec.ProgressDeadlineSeconds) * time.Second
+			if specDeadline < deadline {
+				deadline = specDeadline
+			}
+		}
+		deployments = append(deployments, resource.NewDeployment(d.Name, d.Namespace, deadline))
+	}
+
+	return deployments, nil
+}
+
+// getSkaffoldDeployStatus returns an aggregated error if any resource ended
+// up in an error state.
+func getSkaffoldDeployStatus(rs []Resource) error {
+	var errorStrings []string
+	for _, r := range rs {
+		if err := r.Status().Error(); err != nil {
+			errorStrings = append(errorStrings, fmt.Sprintf("%s failed due to %s", r, err))
+		}
+	}
+
+	if len(errorStrings) == 0 {
+		return nil
+	}
+	return fmt.Errorf("following resources failed to stabilize:\n%s", strings.Join(errorStrings, "\n"))
+}
+
+// printStatus prints the status of every resource not yet complete and
+// reports whether all resources are now complete.
+func printStatus(rs []Resource, out io.Writer) bool {
+	allDone := true
+	for _, r := range rs {
+		if r.IsStatusCheckComplete() {
+			continue
+		}
+		allDone = false
+		fmt.Fprintf(out, " - %s %s\n", r, r.Status())
+	}
+	return allDone
+}
+
+// printStatusCheckSummary prints a final, one-line summary for a resource
+// once its status check has settled.
+func printStatusCheckSummary(out io.Writer, r Resource, pending int, total int) {
+	status := fmt.Sprintf(" - %s", r)
+	if err := r.Status().Error(); err != nil {
+		status += " failed."
+	} else {
+		status += " is ready."
+	}
+	if pending > 0 {
+		status += fmt.Sprintf(" [%d/%d deployment(s) still pending]", pending, total)
+	}
+	if err := r.Status().Error(); err != nil {
+		status += fmt.Sprintf(" Error: %s.", err)
+	}
+	fmt.Fprintln(out, status)
+}